@@ -0,0 +1,217 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// User is an account that owns tracked Sessions.
+type User struct {
+	gorm.Model
+	Email        string `json:"email" gorm:"uniqueIndex;not null"`
+	PasswordHash string `json:"-"`
+}
+
+type registerRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+type loginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type authResponse struct {
+	Token string `json:"token"`
+}
+
+type sessionClaims struct {
+	jwt.RegisteredClaims
+}
+
+var jwtSecretWarnOnce sync.Once
+
+// jwtSecret falls back to an insecure, publicly-known default outside of
+// GIN_MODE=release so local development works without any setup. In
+// release mode a missing JWT_SECRET is a misconfiguration, not something
+// to sign tokens with silently, so the process refuses to serve requests.
+func jwtSecret() []byte {
+	secret := os.Getenv("JWT_SECRET")
+	if secret != "" {
+		return []byte(secret)
+	}
+
+	if os.Getenv("GIN_MODE") == "release" {
+		log.Fatal("JWT_SECRET must be set when GIN_MODE=release")
+	}
+
+	jwtSecretWarnOnce.Do(func() {
+		log.Println("WARNING: JWT_SECRET is not set; using an insecure development default. Never use this outside local development.")
+	})
+	return []byte("dev-secret-change-me")
+}
+
+func jwtTTL() time.Duration {
+	if raw := os.Getenv("JWT_TTL_MINUTES"); raw != "" {
+		if minutes, err := strconv.Atoi(raw); err == nil && minutes > 0 {
+			return time.Duration(minutes) * time.Minute
+		}
+	}
+	return 24 * time.Hour
+}
+
+func issueToken(userID uint) (string, error) {
+	now := time.Now()
+	claims := sessionClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(jwtTTL())),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret())
+}
+
+func registerUser(c *gin.Context) {
+	var req registerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to hash password"})
+		return
+	}
+
+	user := User{Email: req.Email, PasswordHash: string(hash)}
+	if err := db.Create(&user).Error; err != nil {
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "email already registered"})
+		return
+	}
+
+	token, err := issueToken(user.ID)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to issue token"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, authResponse{Token: token})
+}
+
+func loginUser(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user User
+	if err := db.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		c.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "invalid email or password"})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		c.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "invalid email or password"})
+		return
+	}
+
+	token, err := issueToken(user.ID)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to issue token"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, authResponse{Token: token})
+}
+
+// bearerToken extracts the request's JWT from the Authorization header.
+func bearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	const prefix = "Bearer "
+	if len(header) > len(prefix) && header[:len(prefix)] == prefix {
+		return header[len(prefix):]
+	}
+	return ""
+}
+
+// authenticate parses raw as a JWT and stores the authenticated user's ID in
+// the request context under "user_id", aborting the request if raw is empty
+// or invalid.
+func authenticate(c *gin.Context, raw string) {
+	if raw == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+		return
+	}
+
+	claims := &sessionClaims{}
+	token, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return jwtSecret(), nil
+	})
+	if err != nil || !token.Valid {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token subject"})
+		return
+	}
+
+	c.Set("user_id", uint(userID))
+}
+
+// authMiddleware validates the request's bearer token, read from the
+// Authorization header, and stores the authenticated user's ID in the
+// request context under "user_id".
+func authMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticate(c, bearerToken(c))
+		if !c.IsAborted() {
+			c.Next()
+		}
+	}
+}
+
+// wsAuthMiddleware validates the request's bearer token like authMiddleware,
+// but also accepts the token via a "token" query parameter. This fallback is
+// scoped to the WebSocket upgrade routes only, because the browser
+// WebSocket API can't set custom headers on the handshake — unlike plain
+// REST calls, those routes have no other way to authenticate, and a token in
+// a query string is a needless leak into proxy logs and browser history
+// everywhere else.
+func wsAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := bearerToken(c)
+		if raw == "" {
+			raw = c.Query("token")
+		}
+		authenticate(c, raw)
+		if !c.IsAborted() {
+			c.Next()
+		}
+	}
+}
+
+func currentUserID(c *gin.Context) uint {
+	return c.MustGet("user_id").(uint)
+}
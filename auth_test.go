@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestIssueTokenRoundTrip(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	token, err := issueToken(42)
+	if err != nil {
+		t.Fatalf("issueToken() error = %v", err)
+	}
+
+	claims := &sessionClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return jwtSecret(), nil
+	})
+	if err != nil || !parsed.Valid {
+		t.Fatalf("ParseWithClaims() = %v, valid=%v, want valid token", err, parsed.Valid)
+	}
+	if claims.Subject != "42" {
+		t.Errorf("claims.Subject = %q, want %q", claims.Subject, "42")
+	}
+}
+
+func TestIssueTokenRejectedWithWrongSecret(t *testing.T) {
+	t.Setenv("JWT_SECRET", "right-secret")
+	token, err := issueToken(1)
+	if err != nil {
+		t.Fatalf("issueToken() error = %v", err)
+	}
+
+	claims := &sessionClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte("wrong-secret"), nil
+	})
+	if err == nil {
+		t.Fatal("ParseWithClaims() error = nil, want error for mismatched secret")
+	}
+}
+
+func TestJWTTTLDefaultsWhenUnset(t *testing.T) {
+	t.Setenv("JWT_TTL_MINUTES", "")
+	if got := jwtTTL(); got != 24*time.Hour {
+		t.Errorf("jwtTTL() = %v, want %v", got, 24*time.Hour)
+	}
+}
+
+func TestJWTTTLHonorsEnvOverride(t *testing.T) {
+	t.Setenv("JWT_TTL_MINUTES", "5")
+	if got := jwtTTL(); got != 5*time.Minute {
+		t.Errorf("jwtTTL() = %v, want %v", got, 5*time.Minute)
+	}
+}
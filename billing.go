@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// BillingMode controls how elapsed time is rounded into billable time.
+type BillingMode string
+
+const (
+	BillingPerSecond    BillingMode = "per_second"
+	BillingPerMinute    BillingMode = "per_minute"
+	BillingPerHourCeil  BillingMode = "per_hour_ceil"
+	BillingPerHourFloor BillingMode = "per_hour_floor"
+)
+
+// DefaultBillingMode is used when a session doesn't specify one, matching
+// the rounding behaviour the service has always billed with.
+const DefaultBillingMode = BillingPerHourCeil
+
+func normalizeBillingMode(mode BillingMode) BillingMode {
+	switch mode {
+	case BillingPerSecond, BillingPerMinute, BillingPerHourCeil, BillingPerHourFloor:
+		return mode
+	default:
+		return DefaultBillingMode
+	}
+}
+
+// calculatePayment computes the amount owed for elapsed active time,
+// applying the session's billing mode and minimum billable duration. Rate
+// is expressed per hour.
+func calculatePayment(rate float64, mode BillingMode, minBillableSeconds int, elapsed time.Duration) float64 {
+	billableSeconds := billableSecondsFor(normalizeBillingMode(mode), elapsed.Seconds())
+	if min := float64(minBillableSeconds); billableSeconds < min {
+		billableSeconds = min
+	}
+
+	return (billableSeconds / 3600) * rate
+}
+
+func billableSecondsFor(mode BillingMode, elapsedSeconds float64) float64 {
+	switch mode {
+	case BillingPerMinute:
+		return math.Ceil(elapsedSeconds/60) * 60
+	case BillingPerHourCeil:
+		return math.Ceil(elapsedSeconds/3600) * 3600
+	case BillingPerHourFloor:
+		return math.Floor(elapsedSeconds/3600) * 3600
+	default: // BillingPerSecond
+		return math.Ceil(elapsedSeconds)
+	}
+}
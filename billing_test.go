@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBillableSecondsFor(t *testing.T) {
+	tests := []struct {
+		mode    BillingMode
+		elapsed float64
+		want    float64
+	}{
+		{BillingPerSecond, 90, 90},
+		{BillingPerSecond, 90.4, 91},
+		{BillingPerMinute, 61, 120},
+		{BillingPerMinute, 60, 60},
+		{BillingPerHourCeil, 3601, 7200},
+		{BillingPerHourCeil, 3600, 3600},
+		{BillingPerHourFloor, 7199, 3600},
+		{BillingPerHourFloor, 1800, 0},
+	}
+
+	for _, tt := range tests {
+		if got := billableSecondsFor(tt.mode, tt.elapsed); got != tt.want {
+			t.Errorf("billableSecondsFor(%v, %v) = %v, want %v", tt.mode, tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeBillingModeDefaultsUnknown(t *testing.T) {
+	if got := normalizeBillingMode("not-a-real-mode"); got != DefaultBillingMode {
+		t.Errorf("normalizeBillingMode(invalid) = %v, want %v", got, DefaultBillingMode)
+	}
+	if got := normalizeBillingMode(BillingPerMinute); got != BillingPerMinute {
+		t.Errorf("normalizeBillingMode(per_minute) = %v, want %v", got, BillingPerMinute)
+	}
+}
+
+func TestCalculatePaymentDoesNotOverchargeByAnHour(t *testing.T) {
+	// Regression test: the original formula was (ceil(hours)+1)*rate, which
+	// overcharged every session by one full hour.
+	payment := calculatePayment(60, BillingPerHourCeil, 0, time.Hour)
+	if payment != 60 {
+		t.Errorf("calculatePayment() = %v, want %v (exactly one billable hour)", payment, 60.0)
+	}
+}
+
+func TestCalculatePaymentAppliesMinimumBillableSeconds(t *testing.T) {
+	payment := calculatePayment(3600, BillingPerSecond, 600, 30*time.Second)
+	want := (600.0 / 3600) * 3600
+	if payment != want {
+		t.Errorf("calculatePayment() = %v, want %v (minimum billable seconds applied)", payment, want)
+	}
+}
+
+func TestCalculatePaymentZeroElapsed(t *testing.T) {
+	if got := calculatePayment(100, BillingPerHourFloor, 0, 0); got != 0 {
+		t.Errorf("calculatePayment() = %v, want 0", got)
+	}
+}
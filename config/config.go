@@ -0,0 +1,85 @@
+// Package config loads TrackingService's runtime settings from environment
+// variables, optionally layered on top of a YAML file.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting the server needs to boot.
+type Config struct {
+	DBDriver           string   `yaml:"db_driver"`
+	DBDSN              string   `yaml:"db_dsn"`
+	ListenAddr         string   `yaml:"listen_addr"`
+	CORSAllowedOrigins []string `yaml:"cors_allowed_origins"`
+	GinMode            string   `yaml:"gin_mode"`
+	LogLevel           string   `yaml:"log_level"`
+}
+
+func defaults() *Config {
+	return &Config{
+		DBDriver:           "sqlite",
+		DBDSN:              "sessions.db",
+		ListenAddr:         ":8080",
+		CORSAllowedOrigins: []string{"*"},
+		GinMode:            "release",
+		LogLevel:           "info",
+	}
+}
+
+// Load builds a Config starting from defaults, applying CONFIG_FILE (if
+// set) as a YAML overlay, then environment variables, which always win.
+func Load() (*Config, error) {
+	cfg := defaults()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := applyYAMLFile(cfg, path); err != nil {
+			return nil, err
+		}
+	}
+
+	applyEnv(cfg)
+
+	return cfg, nil
+}
+
+func applyYAMLFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file %q: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+func applyEnv(cfg *Config) {
+	if v := os.Getenv("DB_DRIVER"); v != "" {
+		cfg.DBDriver = v
+	}
+	if v := os.Getenv("DB_DSN"); v != "" {
+		cfg.DBDSN = v
+	}
+	if v := os.Getenv("LISTEN_ADDR"); v != "" {
+		cfg.ListenAddr = v
+	}
+	if v := os.Getenv("CORS_ALLOWED_ORIGINS"); v != "" {
+		cfg.CORSAllowedOrigins = strings.Split(v, ",")
+	}
+	if v := os.Getenv("GIN_MODE"); v != "" {
+		cfg.GinMode = v
+	}
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		cfg.LogLevel = v
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jung-kurt/gofpdf"
+)
+
+func parseFlexibleTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", raw)
+}
+
+// exportFilters builds the same session filters the list endpoint uses,
+// pinned to stopped sessions, with "from"/"to" accepted as aliases for
+// started_after/started_before.
+func exportFilters(c *gin.Context) (sessionFilters, error) {
+	filters, err := parseSessionFilters(c)
+	if err != nil {
+		return filters, err
+	}
+	filters.status = "stopped"
+
+	if filters.startedAfter == nil {
+		if raw := c.Query("from"); raw != "" {
+			t, err := parseFlexibleTime(raw)
+			if err != nil {
+				return filters, fmt.Errorf("from must be RFC3339 or YYYY-MM-DD: %w", err)
+			}
+			filters.startedAfter = &t
+		}
+	}
+
+	if filters.startedBefore == nil {
+		if raw := c.Query("to"); raw != "" {
+			t, err := parseFlexibleTime(raw)
+			if err != nil {
+				return filters, fmt.Errorf("to must be RFC3339 or YYYY-MM-DD: %w", err)
+			}
+			filters.startedBefore = &t
+		}
+	}
+
+	return filters, nil
+}
+
+func exportSessions(c *gin.Context) {
+	uid := currentUserID(c)
+
+	filters, err := exportFilters(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var sessions []Session
+	query := applySessionFilters(db.Preload("Intervals").Where("user_id = ?", uid), filters)
+	if err := query.Order("started_at asc").Find(&sessions).Error; err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve sessions"})
+		return
+	}
+
+	switch c.Query("format") {
+	case "csv":
+		writeCSVExport(c, sessions)
+	case "pdf":
+		writePDFExport(c, sessions, c.Query("client"), filters)
+	default:
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "format must be 'csv' or 'pdf'"})
+	}
+}
+
+func writeCSVExport(c *gin.Context, sessions []Session) {
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="sessions.csv"`)
+
+	writer := csv.NewWriter(c.Writer)
+	defer writer.Flush()
+
+	writer.Write([]string{"id", "title", "category", "started_at", "ended_at", "duration_seconds", "rate", "payment"})
+
+	for _, s := range sessions {
+		active := activeDuration(s.Intervals)
+		payment := calculatePayment(s.Rate, s.BillingMode, s.MinimumBillableSeconds, active)
+
+		var endedAt string
+		if s.EndedAt != nil {
+			endedAt = s.EndedAt.Format(time.RFC3339)
+		}
+
+		writer.Write([]string{
+			strconv.FormatUint(uint64(s.ID), 10),
+			s.Title,
+			s.Category,
+			s.StartedAt.Format(time.RFC3339),
+			endedAt,
+			fmt.Sprintf("%.0f", active.Seconds()),
+			fmt.Sprintf("%.2f", s.Rate),
+			fmt.Sprintf("%.2f", payment),
+		})
+	}
+}
+
+func formatDateRange(filters sessionFilters) string {
+	from, to := "earliest", "latest"
+	if filters.startedAfter != nil {
+		from = filters.startedAfter.Format("2006-01-02")
+	}
+	if filters.startedBefore != nil {
+		to = filters.startedBefore.Format("2006-01-02")
+	}
+	return fmt.Sprintf("%s to %s", from, to)
+}
+
+var invoiceColumns = []struct {
+	header string
+	width  float64
+}{
+	{"Title", 45},
+	{"Category", 30},
+	{"Started", 35},
+	{"Ended", 35},
+	{"Duration", 20},
+	{"Payment", 25},
+}
+
+func writePDFExport(c *gin.Context, sessions []Session, client string, filters sessionFilters) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(0, 10, "Invoice")
+	pdf.Ln(12)
+
+	pdf.SetFont("Arial", "", 11)
+	pdf.Cell(0, 7, fmt.Sprintf("Client: %s", client))
+	pdf.Ln(6)
+	pdf.Cell(0, 7, fmt.Sprintf("Invoice #: INV-%d", time.Now().Unix()))
+	pdf.Ln(6)
+	pdf.Cell(0, 7, fmt.Sprintf("Period: %s", formatDateRange(filters)))
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "B", 10)
+	for _, col := range invoiceColumns {
+		pdf.CellFormat(col.width, 8, col.header, "1", 0, "", false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Arial", "", 9)
+	var total float64
+	for _, s := range sessions {
+		active := activeDuration(s.Intervals)
+		payment := calculatePayment(s.Rate, s.BillingMode, s.MinimumBillableSeconds, active)
+		total += payment
+
+		var ended string
+		if s.EndedAt != nil {
+			ended = s.EndedAt.Format("2006-01-02 15:04")
+		}
+
+		row := []string{
+			s.Title,
+			s.Category,
+			s.StartedAt.Format("2006-01-02 15:04"),
+			ended,
+			fmt.Sprintf("%.0fs", active.Seconds()),
+			fmt.Sprintf("%.2f", payment),
+		}
+		for i, cell := range row {
+			pdf.CellFormat(invoiceColumns[i].width, 7, cell, "1", 0, "", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	pdf.Ln(4)
+	pdf.SetFont("Arial", "B", 11)
+	pdf.Cell(0, 8, fmt.Sprintf("Total billable: %.2f", total))
+
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", `attachment; filename="invoice.pdf"`)
+	if err := pdf.Output(c.Writer); err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to render invoice"})
+	}
+}
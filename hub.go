@@ -0,0 +1,59 @@
+package main
+
+import "sync"
+
+// sessionEvent is broadcast to a user's live feed subscribers whenever one
+// of their sessions is created or stopped.
+type sessionEvent struct {
+	Type    string          `json:"type"` // "created" | "stopped"
+	Session SessionResponse `json:"session"`
+}
+
+// eventHub fans session events out to any number of per-user WebSocket
+// subscribers.
+type eventHub struct {
+	mu          sync.Mutex
+	subscribers map[uint]map[chan sessionEvent]bool
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subscribers: make(map[uint]map[chan sessionEvent]bool)}
+}
+
+var hub = newEventHub()
+
+func (h *eventHub) subscribe(userID uint) (chan sessionEvent, func()) {
+	ch := make(chan sessionEvent, 16)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan sessionEvent]bool)
+	}
+	h.subscribers[userID][ch] = true
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		delete(h.subscribers[userID], ch)
+		if len(h.subscribers[userID]) == 0 {
+			delete(h.subscribers, userID)
+		}
+		h.mu.Unlock()
+		close(ch)
+	}
+
+	return ch, unsubscribe
+}
+
+func (h *eventHub) publish(userID uint, evt sessionEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[userID] {
+		select {
+		case ch <- evt:
+		default:
+			// Slow subscriber; drop the event rather than block publishers.
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SessionInterval is one contiguous block of active (unpaused) time within
+// a Session. A session starts with one open interval; pausing closes it and
+// resuming opens a new one.
+type SessionInterval struct {
+	gorm.Model
+	SessionID uint       `json:"session_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+}
+
+func openInterval(sessionID uint, startedAt time.Time) error {
+	return db.Create(&SessionInterval{SessionID: sessionID, StartedAt: startedAt}).Error
+}
+
+func closeOpenInterval(sessionID uint, endedAt time.Time) error {
+	return db.Model(&SessionInterval{}).
+		Where("session_id = ? AND ended_at IS NULL", sessionID).
+		Update("ended_at", endedAt).Error
+}
+
+// backfillMissingIntervals synthesizes one SessionInterval for every
+// session that predates this subsystem and has none yet. Without this, a
+// legacy session that's still running would have zero recorded active
+// time: activeDuration would return 0, sessionStatus would report
+// "paused" instead of "running", and Payment would be computed as 0.
+func backfillMissingIntervals() error {
+	var sessions []Session
+	if err := db.Find(&sessions).Error; err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		var count int64
+		if err := db.Model(&SessionInterval{}).Where("session_id = ?", s.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		interval := SessionInterval{SessionID: s.ID, StartedAt: s.StartedAt}
+		if s.EndedAt != nil {
+			interval.EndedAt = s.EndedAt
+		}
+		if err := db.Create(&interval).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hasOpenInterval(intervals []SessionInterval) bool {
+	for _, iv := range intervals {
+		if iv.EndedAt == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// activeDuration sums the elapsed time of every interval, treating a
+// still-open interval as running until now.
+func activeDuration(intervals []SessionInterval) time.Duration {
+	var total time.Duration
+	for _, iv := range intervals {
+		end := time.Now()
+		if iv.EndedAt != nil {
+			end = *iv.EndedAt
+		}
+		total += end.Sub(iv.StartedAt)
+	}
+	return total
+}
+
+func sessionStatus(s Session) string {
+	switch {
+	case s.EndedAt != nil:
+		return "stopped"
+	case hasOpenInterval(s.Intervals):
+		return "running"
+	default:
+		return "paused"
+	}
+}
+
+func pauseSession(c *gin.Context) {
+	id := c.Param("id")
+	var s Session
+
+	if err := db.Preload("Intervals").Where("user_id = ?", currentUserID(c)).First(&s, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		} else {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve session"})
+		}
+		return
+	}
+
+	if s.EndedAt != nil {
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "session is already stopped"})
+		return
+	}
+	if !hasOpenInterval(s.Intervals) {
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "session is already paused"})
+		return
+	}
+
+	if err := closeOpenInterval(s.ID, time.Now()); err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to pause session"})
+		return
+	}
+
+	if err := db.Preload("Intervals").First(&s, s.ID).Error; err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve updated session"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, toResponse(s))
+}
+
+func resumeSession(c *gin.Context) {
+	id := c.Param("id")
+	var s Session
+
+	if err := db.Preload("Intervals").Where("user_id = ?", currentUserID(c)).First(&s, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		} else {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve session"})
+		}
+		return
+	}
+
+	if s.EndedAt != nil {
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "session is already stopped"})
+		return
+	}
+	if hasOpenInterval(s.Intervals) {
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "session is already running"})
+		return
+	}
+
+	if err := openInterval(s.ID, time.Now()); err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to resume session"})
+		return
+	}
+
+	if err := db.Preload("Intervals").First(&s, s.ID).Error; err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve updated session"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, toResponse(s))
+}
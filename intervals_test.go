@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHasOpenInterval(t *testing.T) {
+	closedEnd := time.Now()
+
+	tests := []struct {
+		name      string
+		intervals []SessionInterval
+		want      bool
+	}{
+		{"no intervals", nil, false},
+		{"all closed", []SessionInterval{{StartedAt: time.Now(), EndedAt: &closedEnd}}, false},
+		{"one open", []SessionInterval{{StartedAt: time.Now(), EndedAt: &closedEnd}, {StartedAt: time.Now()}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasOpenInterval(tt.intervals); got != tt.want {
+				t.Errorf("hasOpenInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActiveDurationSumsClosedAndOpenIntervals(t *testing.T) {
+	now := time.Now()
+	firstEnd := now.Add(-50 * time.Minute)
+
+	intervals := []SessionInterval{
+		{StartedAt: now.Add(-time.Hour), EndedAt: &firstEnd}, // 10m closed
+		{StartedAt: now.Add(-5 * time.Minute)},                // 5m open (until now)
+	}
+
+	got := activeDuration(intervals)
+	want := 15 * time.Minute
+
+	if diff := got - want; diff < -time.Second || diff > time.Second {
+		t.Errorf("activeDuration() = %v, want approximately %v", got, want)
+	}
+}
+
+func TestSessionStatus(t *testing.T) {
+	now := time.Now()
+	stoppedAt := now
+
+	tests := []struct {
+		name    string
+		session Session
+		want    string
+	}{
+		{
+			name:    "stopped",
+			session: Session{EndedAt: &stoppedAt},
+			want:    "stopped",
+		},
+		{
+			name:    "running",
+			session: Session{Intervals: []SessionInterval{{StartedAt: now}}},
+			want:    "running",
+		},
+		{
+			name:    "paused",
+			session: Session{Intervals: []SessionInterval{{StartedAt: now, EndedAt: &stoppedAt}}},
+			want:    "paused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sessionStatus(tt.session); got != tt.want {
+				t.Errorf("sessionStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var logger *zap.Logger
+
+func initLogger(level string) error {
+	zapCfg := zap.NewProductionConfig()
+	parsedLevel, err := zap.ParseAtomicLevel(level)
+	if err == nil {
+		zapCfg.Level = parsedLevel
+	}
+
+	built, err := zapCfg.Build()
+	if err != nil {
+		return err
+	}
+
+	logger = built
+	return nil
+}
+
+// requestLoggingMiddleware assigns a request ID, surfaces it in the
+// X-Request-ID response header, and logs method/path/status/latency once
+// the handler chain completes.
+func requestLoggingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := uuid.NewString()
+		c.Set("request_id", requestID)
+		c.Writer.Header().Set("X-Request-ID", requestID)
+
+		c.Next()
+
+		logger.Info("request",
+			zap.String("request_id", requestID),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("latency", time.Since(start)),
+		)
+	}
+}
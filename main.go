@@ -3,146 +3,246 @@ package main
 import (
 	"fmt"
 	"log"
-	"math"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+
+	"github.com/theokgatsby/TrackingService/config"
 )
 
 type Session struct {
 	gorm.Model
-	Title    string  `json:"title"`
-	Category string  `json:"category"`
-	Rate     float64 `json:"rate"`
+	UserID                 uint              `json:"user_id"`
+	Title                  string            `json:"title"`
+	Category               string            `json:"category"`
+	Rate                   float64           `json:"rate"`
+	BillingMode            BillingMode       `json:"billing_mode"`
+	MinimumBillableSeconds int               `json:"minimum_billable_seconds"`
+	StartedAt              time.Time         `json:"started_at"`
+	EndedAt                *time.Time        `json:"ended_at"`
+	Intervals              []SessionInterval `json:"intervals,omitempty"`
 }
 
 type SessionResponse struct {
-	ID        uint       `json:"id"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
-	Title     string     `json:"title"`
-	Category  string     `json:"category"`
-	Payment   float64    `json:"payment"`
-	Duration  string     `json:"duration"`
+	ID                  uint       `json:"id"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	StartedAt           time.Time  `json:"started_at"`
+	EndedAt             *time.Time `json:"ended_at,omitempty"`
+	Title               string     `json:"title"`
+	Category            string     `json:"category"`
+	Payment             float64    `json:"payment"`
+	Duration            string     `json:"duration"`
+	ActiveDuration      string     `json:"active_duration"`
+	TotalPausedDuration string     `json:"total_paused_duration"`
+	Status              string     `json:"status"`
 }
 
 var db *gorm.DB
 
 func main() {
-	if err := initDB(); err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	router := setupRouter()
-	
-	log.Println("Starting server on :8080")
-	if err := router.Run(":8080"); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if err := initLogger(cfg.LogLevel); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	gin.SetMode(cfg.GinMode)
+
+	if err := initDB(cfg); err != nil {
+		logger.Fatal("failed to initialize database", zap.Error(err))
+	}
+
+	router := setupRouter(cfg)
+
+	logger.Info("starting server", zap.String("addr", cfg.ListenAddr))
+	if err := router.Run(cfg.ListenAddr); err != nil {
+		logger.Fatal("failed to start server", zap.Error(err))
+	}
+}
+
+func dialectorFor(cfg *config.Config) gorm.Dialector {
+	switch cfg.DBDriver {
+	case "postgres":
+		return postgres.Open(cfg.DBDSN)
+	case "mysql":
+		return mysql.Open(cfg.DBDSN)
+	default:
+		return sqlite.Open(cfg.DBDSN)
 	}
 }
 
-func initDB() error {
+func initDB(cfg *config.Config) error {
 	var err error
-	db, err = gorm.Open(sqlite.Open("sessions.db"), &gorm.Config{})
+	db, err = gorm.Open(dialectorFor(cfg), &gorm.Config{})
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	if err := db.AutoMigrate(&Session{}); err != nil {
+	if err := db.AutoMigrate(&User{}, &Session{}, &SessionInterval{}); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	if err := backfillSessionTimestamps(); err != nil {
+		return fmt.Errorf("failed to backfill session timestamps: %w", err)
+	}
+
+	if err := backfillMissingIntervals(); err != nil {
+		return fmt.Errorf("failed to backfill session intervals: %w", err)
+	}
+
 	return nil
 }
 
-func setupRouter() *gin.Engine {
-	router := gin.Default()
+// backfillSessionTimestamps migrates rows created before StartedAt/EndedAt
+// existed. Those rows used CreatedAt as the start time and the soft-delete
+// column DeletedAt to mark a session as stopped; this copies that
+// information into the new columns and clears DeletedAt so the rows behave
+// like normal (non-deleted) records again.
+func backfillSessionTimestamps() error {
+	var legacy []Session
+	if err := db.Unscoped().Where("started_at IS NULL OR started_at = ?", time.Time{}).Find(&legacy).Error; err != nil {
+		return err
+	}
+
+	for _, s := range legacy {
+		updates := map[string]interface{}{"started_at": s.CreatedAt}
+		if s.DeletedAt.Valid {
+			updates["ended_at"] = s.DeletedAt.Time
+		}
 
-	// Add CORS middleware
-	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+		if err := db.Unscoped().Model(&Session{}).Where("id = ?", s.ID).Updates(updates).Error; err != nil {
+			return err
 		}
-		c.Next()
-	})
+		if s.DeletedAt.Valid {
+			if err := db.Unscoped().Model(&Session{}).Where("id = ?", s.ID).Update("deleted_at", nil).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
 
-	router.GET("/sessions", getSessions)
-	router.GET("/sessions/:id", getSessionByID)
-	router.POST("/sessions", postSessions)
-	router.PATCH("/sessions/:id/stop", stopSession)
+func setupRouter(cfg *config.Config) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(requestLoggingMiddleware())
+	router.Use(corsMiddleware(cfg.CORSAllowedOrigins))
+
+	router.POST("/auth/register", registerUser)
+	router.POST("/auth/login", loginUser)
+
+	sessions := router.Group("/sessions")
+	{
+		sessions.GET("", authMiddleware(), getSessions)
+		sessions.GET("/stats", authMiddleware(), getSessionStats)
+		sessions.GET("/export", authMiddleware(), exportSessions)
+		sessions.GET("/live", wsAuthMiddleware(), liveSessionsFeed)
+		sessions.GET("/:id", authMiddleware(), getSessionByID)
+		sessions.GET("/:id/live", wsAuthMiddleware(), liveSession)
+		sessions.POST("", authMiddleware(), postSessions)
+		sessions.PATCH("/:id/stop", authMiddleware(), stopSession)
+		sessions.PATCH("/:id/pause", authMiddleware(), pauseSession)
+		sessions.PATCH("/:id/resume", authMiddleware(), resumeSession)
+	}
 
 	return router
 }
 
 func toResponse(s Session) SessionResponse {
-	var end *time.Time
-	if s.DeletedAt.Valid {
-		end = &s.DeletedAt.Time
-	}
+	active := activeDuration(s.Intervals)
+	payment := calculatePayment(s.Rate, s.BillingMode, s.MinimumBillableSeconds, active)
 
-	payment := calculatePayment(s.Rate, s.CreatedAt, end)
+	wallEnd := time.Now()
+	if s.EndedAt != nil {
+		wallEnd = *s.EndedAt
+	}
+	wallElapsed := wallEnd.Sub(s.StartedAt)
+	paused := wallElapsed - active
 
 	resp := SessionResponse{
-		ID:        s.ID,
-		CreatedAt: s.CreatedAt,
-		UpdatedAt: s.UpdatedAt,
-		Title:     s.Title,
-		Category:  s.Category,
-		Payment:   payment,
+		ID:                  s.ID,
+		CreatedAt:           s.CreatedAt,
+		UpdatedAt:           s.UpdatedAt,
+		StartedAt:           s.StartedAt,
+		Title:               s.Title,
+		Category:            s.Category,
+		Payment:             payment,
+		ActiveDuration:      fmt.Sprintf("%.0fs", active.Seconds()),
+		TotalPausedDuration: fmt.Sprintf("%.0fs", paused.Seconds()),
+		Status:              sessionStatus(s),
 	}
 
-	if end != nil {
-		duration := end.Sub(s.CreatedAt)
-		resp.DeletedAt = end
-		resp.Duration = fmt.Sprintf("%.0fs", duration.Seconds())
+	if s.EndedAt != nil {
+		resp.EndedAt = s.EndedAt
+		resp.Duration = fmt.Sprintf("%.0fs", wallElapsed.Seconds())
 	}
 
 	return resp
 }
 
-func getSessions(c *gin.Context) {
-	var sessions []Session
-	if err := db.Unscoped().Find(&sessions).Error; err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve sessions"})
-		return
-	}
-
-	response := make([]SessionResponse, len(sessions))
-	for i, s := range sessions {
-		response[i] = toResponse(s)
-	}
-
-	c.IndentedJSON(http.StatusOK, response)
+type createSessionRequest struct {
+	Title                  string      `json:"title" binding:"required"`
+	Category               string      `json:"category"`
+	Rate                   float64     `json:"rate"`
+	BillingMode            BillingMode `json:"billing_mode"`
+	MinimumBillableSeconds int         `json:"minimum_billable_seconds"`
 }
 
 func postSessions(c *gin.Context) {
-	var newSession Session
-	if err := c.ShouldBindJSON(&newSession); err != nil {
+	var req createSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	newSession := Session{
+		UserID:                 currentUserID(c),
+		Title:                  req.Title,
+		Category:               req.Category,
+		Rate:                   req.Rate,
+		BillingMode:            normalizeBillingMode(req.BillingMode),
+		MinimumBillableSeconds: req.MinimumBillableSeconds,
+		StartedAt:              time.Now(),
+	}
+
 	if err := db.Create(&newSession).Error; err != nil {
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to create session"})
 		return
 	}
 
-	c.IndentedJSON(http.StatusCreated, toResponse(newSession))
+	if err := openInterval(newSession.ID, newSession.StartedAt); err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to open session interval"})
+		return
+	}
+
+	if err := db.Preload("Intervals").First(&newSession, newSession.ID).Error; err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve created session"})
+		return
+	}
+
+	resp := toResponse(newSession)
+	hub.publish(newSession.UserID, sessionEvent{Type: "created", Session: resp})
+
+	c.IndentedJSON(http.StatusCreated, resp)
 }
 
 func getSessionByID(c *gin.Context) {
 	id := c.Param("id")
 	var s Session
 
-	if err := db.Unscoped().First(&s, id).Error; err != nil {
+	if err := db.Preload("Intervals").Where("user_id = ?", currentUserID(c)).First(&s, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "session not found"})
 		} else {
@@ -158,7 +258,7 @@ func stopSession(c *gin.Context) {
 	id := c.Param("id")
 	var s Session
 
-	if err := db.Unscoped().First(&s, id).Error; err != nil {
+	if err := db.Preload("Intervals").Where("user_id = ?", currentUserID(c)).First(&s, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "session not found"})
 		} else {
@@ -167,36 +267,30 @@ func stopSession(c *gin.Context) {
 		return
 	}
 
-	if s.DeletedAt.Valid {
+	if s.EndedAt != nil {
 		c.IndentedJSON(http.StatusOK, toResponse(s))
 		return
 	}
 
 	now := time.Now()
-	if err := db.Model(&s).Update("deleted_at", now).Error; err != nil {
+	if hasOpenInterval(s.Intervals) {
+		if err := closeOpenInterval(s.ID, now); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to stop session"})
+			return
+		}
+	}
+	if err := db.Model(&s).Update("ended_at", now).Error; err != nil {
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to stop session"})
 		return
 	}
 
-	if err := db.Unscoped().First(&s, id).Error; err != nil {
+	if err := db.Preload("Intervals").First(&s, s.ID).Error; err != nil {
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve updated session"})
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, toResponse(s))
-}
-
-func calculatePayment(rate float64, start time.Time, end *time.Time) float64 {
-	var elapsed time.Duration
-
-	if end != nil {
-		elapsed = end.Sub(start)
-	} else {
-		elapsed = time.Since(start)
-	}
-
-	hours := elapsed.Hours()
-	billableHours := int(math.Ceil(hours))
+	resp := toResponse(s)
+	hub.publish(s.UserID, sessionEvent{Type: "stopped", Session: resp})
 
-	return (float64(billableHours) + 1) * rate
+	c.IndentedJSON(http.StatusOK, resp)
 }
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+type sessionFilters struct {
+	category      string
+	titleContains string
+	startedAfter  *time.Time
+	startedBefore *time.Time
+	status        string
+}
+
+func parseSessionFilters(c *gin.Context) (sessionFilters, error) {
+	var f sessionFilters
+	f.category = c.Query("category")
+	f.titleContains = c.Query("title_contains")
+
+	f.status = c.Query("status")
+	switch f.status {
+	case "", "running", "paused", "stopped":
+	default:
+		return f, fmt.Errorf("status must be 'running', 'paused', or 'stopped'")
+	}
+
+	if raw := c.Query("started_after"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return f, fmt.Errorf("started_after must be RFC3339: %w", err)
+		}
+		f.startedAfter = &t
+	}
+
+	if raw := c.Query("started_before"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return f, fmt.Errorf("started_before must be RFC3339: %w", err)
+		}
+		f.startedBefore = &t
+	}
+
+	return f, nil
+}
+
+func applySessionFilters(query *gorm.DB, f sessionFilters) *gorm.DB {
+	if f.category != "" {
+		query = query.Where("category = ?", f.category)
+	}
+	if f.titleContains != "" {
+		query = query.Where("title LIKE ?", "%"+f.titleContains+"%")
+	}
+	if f.startedAfter != nil {
+		query = query.Where("started_at >= ?", *f.startedAfter)
+	}
+	if f.startedBefore != nil {
+		query = query.Where("started_at <= ?", *f.startedBefore)
+	}
+	switch f.status {
+	case "running":
+		query = query.Where("ended_at IS NULL AND id IN (?)", openIntervalSessionIDs())
+	case "paused":
+		query = query.Where("ended_at IS NULL AND id NOT IN (?)", openIntervalSessionIDs())
+	case "stopped":
+		query = query.Where("ended_at IS NOT NULL")
+	}
+	return query
+}
+
+// openIntervalSessionIDs is a subquery selecting the IDs of sessions that
+// currently have an open (unpaused) interval, used to distinguish
+// "running" from "paused" sessions — both have a nil ended_at.
+func openIntervalSessionIDs() *gorm.DB {
+	return db.Model(&SessionInterval{}).Select("session_id").Where("ended_at IS NULL")
+}
+
+func parsePagination(c *gin.Context) (limit, offset int, err error) {
+	limit = defaultListLimit
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 0 {
+			return 0, 0, fmt.Errorf("limit must be a non-negative integer")
+		}
+		if limit > maxListLimit {
+			limit = maxListLimit
+		}
+	}
+
+	if raw := c.Query("offset"); raw != "" {
+		if offset, err = strconv.Atoi(raw); err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+
+	return limit, offset, nil
+}
+
+func parseSort(c *gin.Context) (sortBy, order string, err error) {
+	sortBy = c.DefaultQuery("sort", "started_at")
+	switch sortBy {
+	case "started_at", "payment", "duration":
+	default:
+		return "", "", fmt.Errorf("sort must be one of 'started_at', 'payment', 'duration'")
+	}
+
+	order = c.DefaultQuery("order", "desc")
+	if order != "asc" && order != "desc" {
+		return "", "", fmt.Errorf("order must be 'asc' or 'desc'")
+	}
+
+	return sortBy, order, nil
+}
+
+// rankSessions orders sessions by a derived field (payment or active
+// duration) that can't be expressed as a SQL ORDER BY clause.
+func rankSessions(sessions []Session, sortBy, order string) []Session {
+	type scored struct {
+		session Session
+		value   float64
+	}
+
+	scoredSessions := make([]scored, len(sessions))
+	for i, s := range sessions {
+		active := activeDuration(s.Intervals)
+		value := active.Seconds()
+		if sortBy == "payment" {
+			value = calculatePayment(s.Rate, s.BillingMode, s.MinimumBillableSeconds, active)
+		}
+		scoredSessions[i] = scored{session: s, value: value}
+	}
+
+	sort.SliceStable(scoredSessions, func(i, j int) bool {
+		if order == "desc" {
+			return scoredSessions[i].value > scoredSessions[j].value
+		}
+		return scoredSessions[i].value < scoredSessions[j].value
+	})
+
+	ranked := make([]Session, len(scoredSessions))
+	for i, sc := range scoredSessions {
+		ranked[i] = sc.session
+	}
+	return ranked
+}
+
+func paginate(sessions []Session, limit, offset int) []Session {
+	if offset >= len(sessions) {
+		return []Session{}
+	}
+	end := offset + limit
+	if end > len(sessions) || limit == 0 {
+		end = len(sessions)
+	}
+	return sessions[offset:end]
+}
+
+func getSessions(c *gin.Context) {
+	uid := currentUserID(c)
+
+	filters, err := parseSessionFilters(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sortBy, order, err := parseSort(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var total int64
+	countQuery := applySessionFilters(db.Model(&Session{}).Where("user_id = ?", uid), filters)
+	if err := countQuery.Count(&total).Error; err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to count sessions"})
+		return
+	}
+
+	fetchQuery := applySessionFilters(db.Preload("Intervals").Where("user_id = ?", uid), filters)
+
+	var sessions []Session
+	if sortBy == "started_at" {
+		fetchQuery = fetchQuery.Order(fmt.Sprintf("started_at %s", order)).Offset(offset)
+		if limit > 0 {
+			fetchQuery = fetchQuery.Limit(limit)
+		}
+		err = fetchQuery.Find(&sessions).Error
+	} else {
+		err = fetchQuery.Find(&sessions).Error
+	}
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve sessions"})
+		return
+	}
+
+	if sortBy != "started_at" {
+		sessions = paginate(rankSessions(sessions, sortBy, order), limit, offset)
+	}
+
+	responses := make([]SessionResponse, len(sessions))
+	for i, s := range sessions {
+		responses[i] = toResponse(s)
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{
+		"data":   responses,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+type categoryStats struct {
+	Category       string  `json:"category"`
+	TotalEarned    float64 `json:"total_earned"`
+	TotalDurationS float64 `json:"total_duration_seconds"`
+	SessionCount   int     `json:"session_count"`
+}
+
+type bucketStats struct {
+	Bucket         string  `json:"bucket"`
+	TotalEarned    float64 `json:"total_earned"`
+	TotalDurationS float64 `json:"total_duration_seconds"`
+}
+
+type statsResponse struct {
+	TotalEarned    float64         `json:"total_earned"`
+	TotalDurationS float64         `json:"total_duration_seconds"`
+	ByCategory     []categoryStats `json:"by_category"`
+	Buckets        []bucketStats   `json:"buckets,omitempty"`
+}
+
+func bucketKey(t time.Time, bucket string) string {
+	switch bucket {
+	case "week":
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case "month":
+		return t.Format("2006-01")
+	default: // "day"
+		return t.Format("2006-01-02")
+	}
+}
+
+func getSessionStats(c *gin.Context) {
+	uid := currentUserID(c)
+
+	filters, err := parseSessionFilters(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bucket := c.Query("bucket")
+	switch bucket {
+	case "", "day", "week", "month":
+	default:
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "bucket must be one of 'day', 'week', 'month'"})
+		return
+	}
+
+	var sessions []Session
+	query := applySessionFilters(db.Preload("Intervals").Where("user_id = ?", uid), filters)
+	if err := query.Find(&sessions).Error; err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve sessions"})
+		return
+	}
+
+	resp := statsResponse{}
+	byCategory := map[string]*categoryStats{}
+	byBucket := map[string]*bucketStats{}
+
+	for _, s := range sessions {
+		active := activeDuration(s.Intervals)
+		payment := calculatePayment(s.Rate, s.BillingMode, s.MinimumBillableSeconds, active)
+		seconds := active.Seconds()
+
+		resp.TotalEarned += payment
+		resp.TotalDurationS += seconds
+
+		cat, ok := byCategory[s.Category]
+		if !ok {
+			cat = &categoryStats{Category: s.Category}
+			byCategory[s.Category] = cat
+		}
+		cat.TotalEarned += payment
+		cat.TotalDurationS += seconds
+		cat.SessionCount++
+
+		if bucket != "" {
+			key := bucketKey(s.StartedAt, bucket)
+			b, ok := byBucket[key]
+			if !ok {
+				b = &bucketStats{Bucket: key}
+				byBucket[key] = b
+			}
+			b.TotalEarned += payment
+			b.TotalDurationS += seconds
+		}
+	}
+
+	categoryKeys := make([]string, 0, len(byCategory))
+	for k := range byCategory {
+		categoryKeys = append(categoryKeys, k)
+	}
+	sort.Strings(categoryKeys)
+	for _, k := range categoryKeys {
+		resp.ByCategory = append(resp.ByCategory, *byCategory[k])
+	}
+
+	if bucket != "" {
+		bucketKeys := make([]string, 0, len(byBucket))
+		for k := range byBucket {
+			bucketKeys = append(bucketKeys, k)
+		}
+		sort.Strings(bucketKeys)
+		for _, k := range bucketKeys {
+			resp.Buckets = append(resp.Buckets, *byBucket[k])
+		}
+	}
+
+	c.IndentedJSON(http.StatusOK, resp)
+}
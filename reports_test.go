@@ -0,0 +1,151 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func contextWithQuery(query string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/sessions?"+query, nil)
+	return c
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantLimit  int
+		wantOffset int
+		wantErr    bool
+	}{
+		{"defaults", "", defaultListLimit, 0, false},
+		{"explicit values", "limit=10&offset=20", 10, 20, false},
+		{"limit capped at max", "limit=1000", maxListLimit, 0, false},
+		{"malformed limit", "limit=5abc", 0, 0, true},
+		{"malformed offset", "offset=5abc", 0, 0, true},
+		{"negative limit", "limit=-1", 0, 0, true},
+		{"negative offset", "offset=-1", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, offset, err := parsePagination(contextWithQuery(tt.query))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parsePagination(%q) error = nil, want error", tt.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePagination(%q) error = %v", tt.query, err)
+			}
+			if limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Errorf("parsePagination(%q) = (%d, %d), want (%d, %d)", tt.query, limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantSortBy string
+		wantOrder  string
+		wantErr    bool
+	}{
+		{"defaults", "", "started_at", "desc", false},
+		{"valid sort and order", "sort=payment&order=asc", "payment", "asc", false},
+		{"invalid sort", "sort=bogus", "", "", true},
+		{"invalid order", "order=sideways", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sortBy, order, err := parseSort(contextWithQuery(tt.query))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseSort(%q) error = nil, want error", tt.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSort(%q) error = %v", tt.query, err)
+			}
+			if sortBy != tt.wantSortBy || order != tt.wantOrder {
+				t.Errorf("parseSort(%q) = (%q, %q), want (%q, %q)", tt.query, sortBy, order, tt.wantSortBy, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func sessionWithDuration(id uint, seconds int, rate float64) Session {
+	start := time.Unix(0, 0)
+	end := start.Add(time.Duration(seconds) * time.Second)
+	return Session{
+		Model:       gorm.Model{ID: id},
+		Rate:        rate,
+		BillingMode: BillingPerSecond,
+		StartedAt:   start,
+		Intervals:   []SessionInterval{{SessionID: id, StartedAt: start, EndedAt: &end}},
+	}
+}
+
+func TestRankSessionsByDuration(t *testing.T) {
+	sessions := []Session{
+		sessionWithDuration(1, 100, 1),
+		sessionWithDuration(2, 300, 1),
+		sessionWithDuration(3, 200, 1),
+	}
+
+	ranked := rankSessions(sessions, "duration", "desc")
+	if got := []uint{ranked[0].ID, ranked[1].ID, ranked[2].ID}; got[0] != 2 || got[1] != 3 || got[2] != 1 {
+		t.Errorf("rankSessions(desc) IDs = %v, want [2 3 1]", got)
+	}
+
+	ranked = rankSessions(sessions, "duration", "asc")
+	if got := []uint{ranked[0].ID, ranked[1].ID, ranked[2].ID}; got[0] != 1 || got[1] != 3 || got[2] != 2 {
+		t.Errorf("rankSessions(asc) IDs = %v, want [1 3 2]", got)
+	}
+}
+
+func TestRankSessionsByPayment(t *testing.T) {
+	sessions := []Session{
+		sessionWithDuration(1, 100, 1),
+		sessionWithDuration(2, 100, 5),
+	}
+
+	ranked := rankSessions(sessions, "payment", "desc")
+	if ranked[0].ID != 2 {
+		t.Errorf("rankSessions(payment, desc)[0].ID = %d, want 2 (higher rate pays more)", ranked[0].ID)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	sessions := []Session{
+		sessionWithDuration(1, 100, 1),
+		sessionWithDuration(2, 100, 1),
+		sessionWithDuration(3, 100, 1),
+	}
+
+	if got := paginate(sessions, 2, 0); len(got) != 2 {
+		t.Errorf("paginate(limit=2, offset=0) returned %d sessions, want 2", len(got))
+	}
+
+	if got := paginate(sessions, 2, 2); len(got) != 1 {
+		t.Errorf("paginate(limit=2, offset=2) returned %d sessions, want 1", len(got))
+	}
+
+	if got := paginate(sessions, 0, 0); len(got) != 3 {
+		t.Errorf("paginate(limit=0, offset=0) returned %d sessions, want 3 (limit=0 means unlimited)", len(got))
+	}
+
+	if got := paginate(sessions, 2, 10); len(got) != 0 {
+		t.Errorf("paginate(limit=2, offset=10) returned %d sessions, want 0 (offset past end)", len(got))
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const defaultLiveTickInterval = time.Second
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+func tickIntervalFromQuery(c *gin.Context) time.Duration {
+	raw := c.Query("interval_seconds")
+	if raw == "" {
+		return defaultLiveTickInterval
+	}
+	if secs, err := strconv.Atoi(raw); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return defaultLiveTickInterval
+}
+
+// liveSession streams the session's updated state every tick interval until
+// it is stopped, at which point it sends a final tick and closes cleanly.
+func liveSession(c *gin.Context) {
+	id := c.Param("id")
+	uid := currentUserID(c)
+
+	var s Session
+	if err := db.Preload("Intervals").Where("user_id = ?", uid).First(&s, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		} else {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve session"})
+		}
+		return
+	}
+
+	interval := tickIntervalFromQuery(c)
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Warn("failed to upgrade websocket connection", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var current Session
+		if err := db.Preload("Intervals").First(&current, s.ID).Error; err != nil {
+			return
+		}
+
+		if err := conn.WriteJSON(toResponse(current)); err != nil {
+			return
+		}
+
+		if current.EndedAt != nil {
+			deadline := time.Now().Add(time.Second)
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "session stopped"), deadline)
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// liveSessionsFeed streams create/stop events for all of the user's
+// sessions so a dashboard can stay in sync without polling.
+func liveSessionsFeed(c *gin.Context) {
+	uid := currentUserID(c)
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Warn("failed to upgrade websocket connection", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	events, unsubscribe := hub.subscribe(uid)
+	defer unsubscribe()
+
+	for {
+		select {
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(evt); err != nil {
+				return
+			}
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}